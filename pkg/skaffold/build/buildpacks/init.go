@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildpacks
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// defaultBuilder is the builder image skaffold init proposes for every
+// buildpacks-eligible project; no builder image is inferred from the project
+// itself.
+const defaultBuilder = "gcr.io/paketo-buildpacks/builder:base"
+
+// languageMarkers are files that, found alongside a project.toml or Procfile,
+// are a strong signal the directory is meant to be built by buildpacks
+// rather than a Dockerfile.
+var languageMarkers = []string{
+	"package.json",
+	"requirements.txt",
+	"pom.xml",
+	"build.gradle",
+	"go.mod",
+}
+
+// Buildpacks holds information about a directory that can be built with
+// Cloud Native Buildpacks.
+type Buildpacks struct {
+	File string `json:"-"`
+}
+
+// Name returns the name of the builder
+func (b Buildpacks) Name() string {
+	return "Buildpacks"
+}
+
+// Describe returns the initBuilder's string representation, used when
+// prompting the user to choose a builder.
+func (b Buildpacks) Describe() string {
+	return fmt.Sprintf("Buildpacks (%s)", b.File)
+}
+
+// UpdateArtifact updates the artifact to be included in the generated Build Config
+func (b Buildpacks) UpdateArtifact(a *latest.Artifact) {
+	a.ArtifactType = latest.ArtifactType{
+		BuildpackArtifact: &latest.BuildpackArtifact{
+			Builder: defaultBuilder,
+		},
+	}
+	a.Workspace = filepath.Dir(b.File)
+}
+
+// ConfiguredImage returns the target image configured by the builder, or
+// empty string if no image is configured. Buildpacks projects don't name
+// their target image, so this is always empty.
+func (b Buildpacks) ConfiguredImage() string {
+	return ""
+}
+
+// Path returns the path to the build definition
+func (b Buildpacks) Path() string {
+	return b.File
+}
+
+// ValidateBuildpacksConfig checks if path is a file that indicates its
+// directory should be built with Cloud Native Buildpacks: either a
+// project.toml/Procfile directly, naming the buildpacks builder config, or a
+// language marker file (package.json, pom.xml, go.mod, ...) in a directory
+// that also contains a project.toml or Procfile. Returns nil if path isn't
+// recognized as a buildpacks project.
+func ValidateBuildpacksConfig(path string) *Buildpacks {
+	base := filepath.Base(path)
+
+	if base == "project.toml" || base == "Procfile" {
+		return &Buildpacks{File: path}
+	}
+
+	isLanguageMarker := false
+	for _, marker := range languageMarkers {
+		if marker == base {
+			isLanguageMarker = true
+			break
+		}
+	}
+	if !isLanguageMarker {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	if projectToml := filepath.Join(dir, "project.toml"); fileExists(projectToml) {
+		return &Buildpacks{File: projectToml}
+	}
+	if procfile := filepath.Join(dir, "Procfile"); fileExists(procfile) {
+		return &Buildpacks{File: procfile}
+	}
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := ioutil.ReadFile(path)
+	return err == nil
+}
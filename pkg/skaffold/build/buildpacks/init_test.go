@@ -0,0 +1,80 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package buildpacks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestValidateBuildpacksConfig(t *testing.T) {
+	testutil.Run(t, "project.toml", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("project.toml", "")
+
+		path := filepath.Join(tmpDir.Root(), "project.toml")
+		b := ValidateBuildpacksConfig(path)
+
+		t.CheckDeepEqual(path, b.File)
+	})
+
+	testutil.Run(t, "Procfile", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("Procfile", "")
+
+		path := filepath.Join(tmpDir.Root(), "Procfile")
+		b := ValidateBuildpacksConfig(path)
+
+		t.CheckDeepEqual(path, b.File)
+	})
+
+	testutil.Run(t, "language marker next to project.toml points at project.toml", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("go.mod", "module example.com/foo").
+			Write("project.toml", "")
+
+		b := ValidateBuildpacksConfig(filepath.Join(tmpDir.Root(), "go.mod"))
+
+		t.CheckDeepEqual(filepath.Join(tmpDir.Root(), "project.toml"), b.File)
+	})
+
+	testutil.Run(t, "language marker next to Procfile points at Procfile", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("package.json", "{}").
+			Write("Procfile", "web: foo")
+
+		b := ValidateBuildpacksConfig(filepath.Join(tmpDir.Root(), "package.json"))
+
+		t.CheckDeepEqual(filepath.Join(tmpDir.Root(), "Procfile"), b.File)
+	})
+
+	testutil.Run(t, "language marker alone is not a buildpacks project", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("go.mod", "module example.com/foo")
+
+		b := ValidateBuildpacksConfig(filepath.Join(tmpDir.Root(), "go.mod"))
+
+		t.CheckDeepEqual((*Buildpacks)(nil), b)
+	})
+
+	testutil.Run(t, "unrelated file is not a buildpacks project", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("main.go", "package main")
+
+		b := ValidateBuildpacksConfig(filepath.Join(tmpDir.Root(), "main.go"))
+
+		t.CheckDeepEqual((*Buildpacks)(nil), b)
+	})
+}
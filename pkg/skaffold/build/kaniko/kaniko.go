@@ -18,22 +18,39 @@ package kaniko
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/kaniko/sources"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker/auth"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// dockerAuthConfigEnv is read by skaffold's kaniko init container, which
+// writes its value out to /kaniko/.docker/config.json before the kaniko
+// executor starts. With UseAuthorizer set, it takes the place of the mounted
+// pull-secret Volume that b.setupSecret provides otherwise.
+const dockerAuthConfigEnv = "DOCKER_AUTH_CONFIG_JSON"
+
 // Build builds a list of artifacts with Kaniko.
 func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	if b.KanikoBuild.UseAuthorizer {
+		// Credentials are minted per pod from the authorizer instead, see
+		// credentialsEnv.
+		return build.InParallel(ctx, out, tagger, artifacts, b.buildArtifactWithKaniko)
+	}
+
 	teardown, err := b.setupSecret(out)
 	if err != nil {
 		return nil, errors.Wrap(err, "setting up secret")
@@ -43,6 +60,38 @@ func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, a
 	return build.InParallel(ctx, out, tagger, artifacts, b.buildArtifactWithKaniko)
 }
 
+// credentialsEnv resolves a short-lived credential for fqn's registry
+// through the pluggable authorizer and packages it as docker's own
+// config.json `auths` shape, so a single env var carries exactly what a
+// mounted pull-secret used to.
+func credentialsEnv(ctx context.Context, fqn string) (*corev1.EnvVar, error) {
+	registry := strings.SplitN(fqn, "/", 2)[0]
+
+	cred, err := auth.NewDefaultAuthorizer().Authorize(ctx, registry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "authorizing for registry %s", registry)
+	}
+
+	authEntry := cred.Username + ":" + cred.Password
+	if cred.Token != "" {
+		authEntry = cred.Token
+	}
+
+	config := map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte(authEntry)),
+			},
+		},
+	}
+	content, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling docker config")
+	}
+
+	return &corev1.EnvVar{Name: dockerAuthConfigEnv, Value: string(content)}, nil
+}
+
 func (b *Builder) buildArtifactWithKaniko(ctx context.Context, out io.Writer, artifact *latest.Artifact, fqn string) (string, error) {
 	s := sources.Retrieve(b.KanikoBuild)
 	context, err := s.Setup(ctx, out, artifact, fqn)
@@ -71,8 +120,17 @@ func (b *Builder) buildArtifactWithKaniko(ctx context.Context, out io.Writer, ar
 		}
 	}
 
+	pod := s.Pod(args)
+	if b.KanikoBuild.UseAuthorizer {
+		env, err := credentialsEnv(ctx, fqn)
+		if err != nil {
+			return "", errors.Wrap(err, "resolving registry credentials")
+		}
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, *env)
+	}
+
 	pods := client.CoreV1().Pods(b.Namespace)
-	p, err := pods.Create(s.Pod(args))
+	p, err := pods.Create(pod)
 	if err != nil {
 		return "", errors.Wrap(err, "creating kaniko pod")
 	}
@@ -96,5 +154,30 @@ func (b *Builder) buildArtifactWithKaniko(ctx context.Context, out io.Writer, ar
 
 	waitForLogs()
 
-	return docker.FullRemoteReference(fqn)
+	ref, err := docker.FullRemoteReference(fqn)
+	if err != nil {
+		return "", err
+	}
+
+	if b.KanikoBuild.OutputTimestamp != "" {
+		// Kaniko builds in-cluster with no local Docker daemon to enumerate
+		// dependencies from, so SourceTimestamp falls back to the workspace's
+		// git commit time.
+		t, err := docker.ResolveOutputTimestamp(b.KanikoBuild.OutputTimestamp, artifact.Workspace, nil)
+		if err != nil {
+			return "", errors.Wrap(err, "resolving output timestamp")
+		}
+
+		if err := docker.SetRemoteImageTimestamp(ref, t); err != nil {
+			return "", errors.Wrap(err, "rewriting image timestamp")
+		}
+	}
+
+	if b.KanikoBuild.ArchiveOutput != "" {
+		if err := docker.WriteRemoteArchive(b.KanikoBuild.ArchiveOutput, ref); err != nil {
+			return "", errors.Wrap(err, "writing image archive")
+		}
+	}
+
+	return ref, nil
 }
@@ -0,0 +1,90 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// buildBazel builds an artifact with Bazel and loads the resulting image
+// into the local docker daemon under fqn, via `bazel run` against the
+// rules_docker target the same way `bazel run //target` would.
+func (b *Builder) buildBazel(ctx context.Context, out io.Writer, workspace string, a *latest.BazelArtifact, fqn string) (string, error) {
+	buildArgs := append([]string{"build"}, a.BuildArgs...)
+
+	if b.cfg.OutputTimestamp != "" {
+		t, err := docker.ResolveOutputTimestamp(b.cfg.OutputTimestamp, workspace, nil)
+		if err != nil {
+			return "", errors.Wrap(err, "resolving output timestamp")
+		}
+
+		// --stamp makes the workspace status command's key/value pairs
+		// available to a container_image rule's creation_time attribute, so
+		// two builds over the same source tree produce the same timestamp.
+		buildArgs = append(buildArgs, "--stamp",
+			fmt.Sprintf("--workspace_status_command=echo STABLE_SOURCE_DATE_EPOCH %d", t.Unix()))
+	}
+	buildArgs = append(buildArgs, a.BuildTarget)
+
+	if err := runBazel(ctx, out, workspace, buildArgs); err != nil {
+		return "", errors.Wrap(err, "running bazel build")
+	}
+
+	runCmd := exec.CommandContext(ctx, "bazel", "run", a.BuildTarget, "--", "--norun")
+	runCmd.Dir = workspace
+	runCmd.Env = append(runCmd.Env, "DOCKER_TAG="+fqn)
+	runCmd.Stdout = out
+	runCmd.Stderr = out
+	if err := util.RunCmd(runCmd); err != nil {
+		return "", errors.Wrap(err, "loading bazel image into the local daemon")
+	}
+
+	if b.cfg.ArchiveOutput != "" {
+		if err := docker.WriteArchive(b.cfg.ArchiveOutput, fqn); err != nil {
+			return "", errors.Wrap(err, "writing image archive")
+		}
+	}
+
+	if b.pushImages {
+		digest, err := b.localDocker.Push(ctx, out, fqn)
+		if err != nil {
+			return "", errors.Wrap(err, "pushing image")
+		}
+
+		return fmt.Sprintf("%s@%s", fqn, digest), nil
+	}
+
+	return fqn, nil
+}
+
+func runBazel(ctx context.Context, out io.Writer, workspace string, args []string) error {
+	cmd := exec.CommandContext(ctx, "bazel", args...)
+	cmd.Dir = workspace
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/util/progress/progressui"
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// defaultBuildkitAddr is tried when the user hasn't configured an explicit
+// BuildkitAddr, matching buildkitd's own default listening socket.
+const defaultBuildkitAddr = "unix:///run/buildkit/buildkitd.sock"
+
+// buildkitClient dials the configured (or autodetected) buildkitd, so
+// skaffold can submit LLB graphs without depending on a local docker daemon.
+func (b *Builder) buildkitClient(ctx context.Context) (*client.Client, error) {
+	addr := b.cfg.BuildkitAddr
+	if addr == "" {
+		addr = defaultBuildkitAddr
+	}
+
+	return client.New(ctx, addr, client.WithFailFast())
+}
+
+// solve runs a solve request against buildkitd, streaming its status channel
+// into out so users see the same interleaved step progress they're used to,
+// and returns the image digest reported by the image exporter.
+func (b *Builder) solve(ctx context.Context, out io.Writer, req client.SolveOpt, def *llb.Definition) (string, error) {
+	c, err := b.buildkitClient(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "connecting to buildkitd")
+	}
+	defer c.Close()
+
+	statusCh := make(chan *client.SolveStatus)
+
+	var resp *client.SolveResponse
+	var solveErr error
+	done := make(chan struct{})
+	go func() {
+		resp, solveErr = c.Solve(ctx, def, req, statusCh)
+		close(done)
+	}()
+
+	if _, err := progressui.DisplaySolveStatus(ctx, "", nil, out, statusCh); err != nil {
+		return "", errors.Wrap(err, "displaying build progress")
+	}
+	<-done
+
+	if solveErr != nil {
+		return "", solveErr
+	}
+
+	return resp.ExporterResponse["containerimage.digest"], nil
+}
+
+// buildkitBuildDockerfile builds a DockerArtifact by submitting it to
+// buildkitd through the existing dockerfile frontend, so that the LLB graph
+// is built the same way `DOCKER_BUILDKIT=1 docker build` would build it.
+func (b *Builder) buildkitBuildDockerfile(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, fqn string) (string, error) {
+	attrs := map[string]string{
+		"filename": a.DockerfilePath,
+	}
+	for k, v := range a.BuildArgs {
+		if v != nil {
+			attrs["build-arg:"+k] = *v
+		}
+	}
+	if a.Target != "" {
+		attrs["target"] = a.Target
+	}
+
+	req := client.SolveOpt{
+		Frontend:      "dockerfile.v0",
+		FrontendAttrs: attrs,
+		LocalDirs: map[string]string{
+			"context":    workspace,
+			"dockerfile": workspace,
+		},
+		Exports: []client.ExportEntry{{
+			Type: client.ExporterImage,
+			Attrs: map[string]string{
+				"name": fqn,
+				"push": fmt.Sprintf("%t", b.pushImages),
+			},
+		}},
+	}
+
+	digest, err := b.solve(ctx, out, req, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "running buildkit build")
+	}
+
+	if b.cfg.OutputTimestamp != "" || b.cfg.ArchiveOutput != "" {
+		if !b.pushImages || digest == "" {
+			return "", errors.New("useBuildkit requires pushImages when outputTimestamp or archiveOutput is set, since the built image only exists in the registry, not the local daemon")
+		}
+
+		ref := fmt.Sprintf("%s@%s", fqn, digest)
+
+		if b.cfg.OutputTimestamp != "" {
+			deps, err := docker.GetDependencies(ctx, workspace, a.DockerfilePath, a.BuildArgs, b.localDocker)
+			if err != nil {
+				return "", errors.Wrap(err, "getting dependencies")
+			}
+
+			t, err := docker.ResolveOutputTimestamp(b.cfg.OutputTimestamp, workspace, deps)
+			if err != nil {
+				return "", errors.Wrap(err, "resolving output timestamp")
+			}
+
+			if err := docker.SetRemoteImageTimestamp(ref, t); err != nil {
+				return "", errors.Wrap(err, "rewriting image timestamp")
+			}
+		}
+
+		if b.cfg.ArchiveOutput != "" {
+			if err := docker.WriteRemoteArchive(b.cfg.ArchiveOutput, ref); err != nil {
+				return "", errors.Wrap(err, "writing image archive")
+			}
+		}
+	}
+
+	if b.pushImages && digest != "" {
+		return fmt.Sprintf("%s@%s", fqn, digest), nil
+	}
+
+	return fqn, nil
+}
+
+// llbWorkspaceMount is where the host workspace is bind-mounted into every
+// command an LLBArtifact runs, mirroring the "context" LocalDirs entry
+// buildkitBuildDockerfile sets up for the dockerfile frontend.
+const llbWorkspaceMount = "/workspace"
+
+// buildLLB builds an LLBArtifact by constructing the LLB graph directly,
+// without going through the dockerfile frontend: a base image, with the
+// host workspace mounted in and each command run against it in turn.
+func (b *Builder) buildLLB(ctx context.Context, out io.Writer, workspace string, a *latest.LLBArtifact, fqn string) (string, error) {
+	src := llb.Local("context")
+
+	state := llb.Image(a.Image)
+	for _, command := range a.Commands {
+		run := state.Run(llb.Shlex(command), llb.Dir(llbWorkspaceMount))
+		run.AddMount(llbWorkspaceMount, src)
+		state = run.Root()
+	}
+
+	def, err := state.Marshal(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "marshalling LLB graph")
+	}
+
+	req := client.SolveOpt{
+		LocalDirs: map[string]string{
+			"context": workspace,
+		},
+		Exports: []client.ExportEntry{{
+			Type: client.ExporterImage,
+			Attrs: map[string]string{
+				"name": fqn,
+				"push": fmt.Sprintf("%t", b.pushImages),
+			},
+		}},
+	}
+
+	digest, err := b.solve(ctx, out, req, def)
+	if err != nil {
+		return "", errors.Wrap(err, "running buildkit build")
+	}
+
+	if b.pushImages && digest != "" {
+		return fmt.Sprintf("%s@%s", fqn, digest), nil
+	}
+
+	return fqn, nil
+}
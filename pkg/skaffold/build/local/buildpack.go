@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// buildBuildpack builds an artifact with Cloud Native Buildpacks, using the
+// `pack` CLI against the local docker daemon. No Dockerfile is required:
+// `pack` runs the builder image's detect and build phases and leaves the
+// resulting image in the local daemon, ready to be tagged and pushed like
+// any other artifact.
+func (b *Builder) buildBuildpack(ctx context.Context, out io.Writer, workspace string, a *latest.BuildpackArtifact, fqn string) (string, error) {
+	args := []string{"build", fqn, "--path", workspace, "--builder", a.Builder, "--no-pull"}
+
+	if a.RunImage != "" {
+		args = append(args, "--run-image", a.RunImage)
+	}
+	if a.CacheImage != "" {
+		args = append(args, "--cache-image", a.CacheImage)
+	}
+	for _, buildpack := range a.Buildpacks {
+		args = append(args, "--buildpack", buildpack)
+	}
+	for k, v := range a.Env {
+		args = append(args, "--env", fmt.Sprintf("%s=%s", k, v))
+	}
+
+	cmd := exec.CommandContext(ctx, "pack", args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	if err := util.RunCmd(cmd); err != nil {
+		return "", errors.Wrap(err, "running pack build")
+	}
+
+	if b.pushImages {
+		digest, err := b.localDocker.Push(ctx, out, fqn)
+		if err != nil {
+			return "", errors.Wrap(err, "pushing image")
+		}
+
+		return fmt.Sprintf("%s@%s", fqn, digest), nil
+	}
+
+	return fqn, nil
+}
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"os/exec"
 
 	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
@@ -30,7 +29,14 @@ import (
 )
 
 func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, fqn string) (string, error) {
-	if b.cfg.UseDockerCLI || b.cfg.UseBuildkit {
+	if b.cfg.UseBuildkit {
+		// buildkitd exports (and optionally pushes) the image itself, so we
+		// don't go through the local daemon at all.
+		return b.buildkitBuildDockerfile(ctx, out, workspace, a, fqn)
+	}
+
+	switch {
+	case b.cfg.UseDockerCLI:
 		dockerfilePath, err := docker.NormalizeDockerfilePath(workspace, a.DockerfilePath)
 		if err != nil {
 			return "", errors.Wrap(err, "normalizing dockerfile path")
@@ -40,21 +46,31 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 		args = append(args, docker.GetBuildArgs(a)...)
 
 		cmd := exec.CommandContext(ctx, "docker", args...)
-		if b.cfg.UseBuildkit {
-			cmd.Env = append(os.Environ(), "DOCKER_BUILDKIT=1")
-		}
 		cmd.Stdout = out
 		cmd.Stderr = out
 
 		if err := util.RunCmd(cmd); err != nil {
 			return "", errors.Wrap(err, "running build")
 		}
-	} else {
+
+	default:
 		if _, err := b.localDocker.Build(ctx, out, workspace, a, fqn); err != nil {
 			return "", errors.Wrap(err, "running build")
 		}
 	}
 
+	if b.cfg.OutputTimestamp != "" {
+		if err := b.rewriteTimestamp(ctx, workspace, a, fqn); err != nil {
+			return "", errors.Wrap(err, "rewriting image timestamp")
+		}
+	}
+
+	if b.cfg.ArchiveOutput != "" {
+		if err := docker.WriteArchive(b.cfg.ArchiveOutput, fqn); err != nil {
+			return "", errors.Wrap(err, "writing image archive")
+		}
+	}
+
 	if b.pushImages {
 		digest, err := b.localDocker.Push(ctx, out, fqn)
 		if err != nil {
@@ -66,3 +82,20 @@ func (b *Builder) buildDocker(ctx context.Context, out io.Writer, workspace stri
 
 	return fqn, nil
 }
+
+// rewriteTimestamp resolves b.cfg.OutputTimestamp against the artifact's
+// dependencies and rewrites the just-built image's timestamps accordingly,
+// so that repeated builds of the same source tree produce identical images.
+func (b *Builder) rewriteTimestamp(ctx context.Context, workspace string, a *latest.DockerArtifact, fqn string) error {
+	deps, err := docker.GetDependencies(ctx, workspace, a.DockerfilePath, a.BuildArgs, b.localDocker)
+	if err != nil {
+		return errors.Wrap(err, "getting dependencies")
+	}
+
+	t, err := docker.ResolveOutputTimestamp(b.cfg.OutputTimestamp, workspace, deps)
+	if err != nil {
+		return errors.Wrap(err, "resolving output timestamp")
+	}
+
+	return docker.SetImageTimestamp(fqn, t)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/util"
+)
+
+// buildJibMaven builds an artifact with the jib-maven-plugin. Jib pushes the
+// image itself and never touches a local docker daemon, so there's no image
+// for us to rewrite the timestamp of after the fact the way buildDocker
+// does -- instead we pass the resolved OutputTimestamp
+// straight through as jib's own container properties.
+func (b *Builder) buildJibMaven(ctx context.Context, out io.Writer, workspace string, a *latest.JibMavenArtifact, fqn string) (string, error) {
+	goal := "jib:build"
+	args := []string{"-Dimage=" + fqn}
+	if a.Profile != "" {
+		args = append(args, "-P"+a.Profile)
+	}
+	if a.Module != "" {
+		args = append(args, "-pl", a.Module, "-am")
+	}
+	if b.cfg.ArchiveOutput != "" {
+		goal = "jib:buildTar"
+		args = append(args, "-Djib.outputPaths.tar="+b.cfg.ArchiveOutput)
+	}
+
+	timestampArgs, err := jibTimestampArgs(b.cfg, workspace)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, timestampArgs...)
+	args = append(args, goal)
+
+	if err := runJib(ctx, out, workspace, "mvn", "mvnw", args); err != nil {
+		return "", errors.Wrap(err, "running jib:build")
+	}
+
+	return fqn, nil
+}
+
+// buildJibGradle builds an artifact with the Jib Gradle plugin.
+func (b *Builder) buildJibGradle(ctx context.Context, out io.Writer, workspace string, a *latest.JibGradleArtifact, fqn string) (string, error) {
+	task := "jib"
+	if b.cfg.ArchiveOutput != "" {
+		task = "jibBuildTar"
+	}
+	if a.Project != "" {
+		task = a.Project + ":" + task
+	}
+
+	args := []string{task, "--image=" + fqn}
+	if b.cfg.ArchiveOutput != "" {
+		args = append(args, "-Djib.outputPaths.tar="+b.cfg.ArchiveOutput)
+	}
+
+	timestampArgs, err := jibTimestampArgs(b.cfg, workspace)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, timestampArgs...)
+
+	if err := runJib(ctx, out, workspace, "gradle", "gradlew", args); err != nil {
+		return "", errors.Wrap(err, "running jib build")
+	}
+
+	return fqn, nil
+}
+
+// jibTimestampArgs turns cfg.OutputTimestamp into the -Djib.container.*
+// system properties that make jib's image config reproducible.
+func jibTimestampArgs(cfg docker.Config, workspace string) ([]string, error) {
+	if cfg.OutputTimestamp == "" {
+		return nil, nil
+	}
+
+	t, err := docker.ResolveOutputTimestamp(cfg.OutputTimestamp, workspace, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "resolving output timestamp")
+	}
+
+	stamp := t.UTC().Format(time.RFC3339)
+	return []string{
+		"-Djib.container.creationTime=" + stamp,
+		"-Djib.container.filesModificationTime=" + stamp,
+	}, nil
+}
+
+// runJib shells out to executable (preferring a project-local wrapper, the
+// same way ValidateJibConfig looks for one at init time) with args,
+// streaming its output to out.
+func runJib(ctx context.Context, out io.Writer, workspace, executable, wrapper string, args []string) error {
+	bin := executable
+	if wrapperPath, err := util.AbsFile(workspace, wrapper); err == nil {
+		bin = wrapperPath
+	}
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = workspace
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	return util.RunCmd(cmd)
+}
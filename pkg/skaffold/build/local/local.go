@@ -53,6 +53,12 @@ func (b *Builder) buildArtifactLocally(ctx context.Context, out io.Writer, artif
 	case artifact.JibGradleArtifact != nil:
 		return b.buildJibGradle(ctx, out, artifact.Workspace, artifact.JibGradleArtifact, fqn)
 
+	case artifact.BuildpackArtifact != nil:
+		return b.buildBuildpack(ctx, out, artifact.Workspace, artifact.BuildpackArtifact, fqn)
+
+	case artifact.LLBArtifact != nil:
+		return b.buildLLB(ctx, out, artifact.Workspace, artifact.LLBArtifact, fqn)
+
 	default:
 		return "", fmt.Errorf("undefined artifact type: %+v", artifact.ArtifactType)
 	}
@@ -0,0 +1,41 @@
+/*
+Copyright 2018 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import "github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker"
+
+// Builder is an artifact builder that runs builds against the local docker
+// daemon, or the cluster's docker daemon when running against minikube/kind.
+type Builder struct {
+	cfg         docker.Config
+	localDocker docker.LocalDaemon
+
+	localCluster bool
+	kubeContext  string
+	pushImages   bool
+}
+
+// NewBuilder returns a new local.Builder.
+func NewBuilder(cfg docker.Config, localDocker docker.LocalDaemon, localCluster bool, kubeContext string, pushImages bool) *Builder {
+	return &Builder{
+		cfg:          cfg,
+		localDocker:  localDocker,
+		localCluster: localCluster,
+		kubeContext:  kubeContext,
+		pushImages:   pushImages,
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton
+
+import (
+	tektonclientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+)
+
+// tektonClientset returns a client for the Tekton CRDs, built from the same
+// kubeconfig/context skaffold already uses to talk to the cluster.
+func tektonClientset() (tektonclientset.Interface, error) {
+	restConfig, err := kubernetes.GetRestClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return tektonclientset.NewForConfig(restConfig)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	tektontyped "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1alpha1"
+	"knative.dev/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	k8sclient "k8s.io/client-go/kubernetes"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build/tag"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/kubernetes"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// imageDigestResult is the name of the PipelineResult that the pre-installed
+// pipeline is expected to set to the fully-qualified `image@sha256:...`
+// reference of the image it pushed.
+const imageDigestResult = "IMAGE-DIGEST"
+
+// pollInterval is how often we poll the PipelineRun's status while waiting
+// for it to complete.
+const pollInterval = 2 * time.Second
+
+// Build builds a list of artifacts by submitting a Tekton PipelineRun per
+// artifact to the cluster.
+func (b *Builder) Build(ctx context.Context, out io.Writer, tagger tag.Tagger, artifacts []*latest.Artifact) ([]build.Artifact, error) {
+	return build.InParallel(ctx, out, tagger, artifacts, b.buildArtifactWithTekton)
+}
+
+func (b *Builder) buildArtifactWithTekton(ctx context.Context, out io.Writer, artifact *latest.Artifact, fqn string) (string, error) {
+	tektonClient, err := tektonClientset()
+	if err != nil {
+		return "", errors.Wrap(err, "getting tekton clientset")
+	}
+	kubeClient, err := kubernetes.GetClientset()
+	if err != nil {
+		return "", errors.Wrap(err, "getting kubernetes clientset")
+	}
+
+	runs := tektonClient.TektonV1alpha1().PipelineRuns(b.Namespace)
+
+	created, err := runs.Create(b.pipelineRun(artifact, fqn))
+	if err != nil {
+		return "", errors.Wrap(err, "creating pipelinerun")
+	}
+	defer func() {
+		if err := runs.Delete(created.Name, &metav1.DeleteOptions{}); err != nil {
+			logrus.Warnf("deleting pipelinerun %s: %s", created.Name, err)
+		}
+	}()
+
+	fmt.Fprintf(out, "Starting PipelineRun %s for artifact %s\n", created.Name, artifact.ImageName)
+
+	completed, err := waitForPipelineRunComplete(ctx, runs, created.Name, b.timeout)
+	if err != nil {
+		return "", errors.Wrap(err, "waiting for pipelinerun to complete")
+	}
+
+	if err := streamTaskLogs(out, kubeClient, b.Namespace, completed.Name); err != nil {
+		logrus.Warnf("streaming task logs: %s", err)
+	}
+
+	for _, result := range completed.Status.PipelineResults {
+		if result.Name == imageDigestResult {
+			return validateImageDigest(result.Value, fqn)
+		}
+	}
+
+	return "", fmt.Errorf("pipelinerun %s did not report a %s result", created.Name, imageDigestResult)
+}
+
+// validateImageDigest checks that ref is a fully-qualified `fqn@sha256:...`
+// reference for the image we actually asked the pipeline to build, so a
+// misconfigured (or malicious) pipeline can't make skaffold deploy an
+// arbitrary image by reporting a bogus IMAGE-DIGEST result.
+func validateImageDigest(ref, fqn string) (string, error) {
+	prefix := fqn + "@sha256:"
+	if !strings.HasPrefix(ref, prefix) || len(ref) != len(prefix)+64 {
+		return "", fmt.Errorf("pipelinerun reported %s result %q, which isn't a valid digest for %s", imageDigestResult, ref, fqn)
+	}
+
+	if _, err := hex.DecodeString(ref[len(prefix):]); err != nil {
+		return "", fmt.Errorf("pipelinerun reported %s result %q, which isn't a valid digest for %s", imageDigestResult, ref, fqn)
+	}
+
+	return ref, nil
+}
+
+// pipelineRun builds the PipelineRun to submit for a single artifact. The
+// artifact's workspace, dockerfile and target image are passed through as
+// params to the pre-installed pipeline named by b.PipelineName (such as a
+// git-clone -> kaniko/buildpacks/S2I chain), so the pipeline itself owns how
+// the image is actually produced. No default pipeline name is applied.
+func (b *Builder) pipelineRun(artifact *latest.Artifact, fqn string) *tektonv1alpha1.PipelineRun {
+	params := []tektonv1alpha1.Param{
+		{Name: "context", Value: artifact.Workspace},
+		{Name: "image", Value: fqn},
+	}
+	if artifact.DockerArtifact != nil {
+		params = append(params, tektonv1alpha1.Param{Name: "dockerfile", Value: artifact.DockerArtifact.DockerfilePath})
+	}
+
+	return &tektonv1alpha1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "skaffold-",
+			Namespace:    b.Namespace,
+		},
+		Spec: tektonv1alpha1.PipelineRunSpec{
+			PipelineRef:        &tektonv1alpha1.PipelineRef{Name: b.PipelineName},
+			ServiceAccountName: b.ServiceAccountName,
+			Params:             params,
+			Workspaces: []tektonv1alpha1.WorkspaceBinding{{
+				Name: "source",
+				PersistentVolumeClaim: &tektonv1alpha1.PersistentVolumeClaimVolumeSource{
+					ClaimName: b.Workspace,
+				},
+			}},
+		},
+	}
+}
+
+// waitForPipelineRunComplete polls the PipelineRun until its Succeeded
+// condition is no longer Unknown, or ctx is done/timeout elapses.
+func waitForPipelineRunComplete(ctx context.Context, runs tektontyped.PipelineRunInterface, name string, timeout time.Duration) (*tektonv1alpha1.PipelineRun, error) {
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-time.After(timeout):
+		}
+		close(stop)
+	}()
+
+	var result *tektonv1alpha1.PipelineRun
+	err := wait.PollImmediateUntil(pollInterval, func() (bool, error) {
+		run, err := runs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		condition := run.Status.GetCondition(apis.ConditionSucceeded)
+		if condition == nil || condition.IsUnknown() {
+			return false, nil
+		}
+
+		result = run
+		if condition.IsFalse() {
+			return true, fmt.Errorf("pipelinerun %s failed: %s", name, condition.Message)
+		}
+		return true, nil
+	}, stop)
+
+	return result, err
+}
+
+// streamTaskLogs prints the logs of every pod created for the PipelineRun's
+// underlying TaskRuns, identified by Tekton's standard pipelinerun label.
+func streamTaskLogs(out io.Writer, client k8sclient.Interface, namespace, pipelineRunName string) error {
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("tekton.dev/pipelineRun=%s", pipelineRunName),
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing pipelinerun pods")
+	}
+
+	for _, pod := range pods.Items {
+		logs, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).Stream()
+		if err != nil {
+			logrus.Warnf("getting logs for pod %s: %s", pod.Name, err)
+			continue
+		}
+
+		fmt.Fprintf(out, "--- %s ---\n", pod.Name)
+		_, copyErr := io.Copy(out, logs)
+		logs.Close()
+		if copyErr != nil {
+			return copyErr
+		}
+	}
+
+	return nil
+}
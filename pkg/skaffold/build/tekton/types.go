@@ -0,0 +1,43 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tekton
+
+import (
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// defaultTimeout is how long Builder waits for a PipelineRun to finish
+// before giving up.
+const defaultTimeout = 20 * time.Minute
+
+// Builder builds artifacts on-cluster by submitting Tekton PipelineRuns
+// against a pipeline that's already installed in the target cluster.
+type Builder struct {
+	*latest.TektonBuild
+
+	timeout time.Duration
+}
+
+// NewBuilder returns a new tekton.Builder.
+func NewBuilder(cfg *latest.TektonBuild) *Builder {
+	return &Builder{
+		TektonBuild: cfg,
+		timeout:     defaultTimeout,
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/build"
+)
+
+// WriteArchive builds ref from the local daemon into an OCI image layout
+// (oci-layout, index.json, blobs/sha256/*) and tars that layout up into a
+// single archive at dest -- the same interchange format containerd uses for
+// `ctr images export`. This lets air-gapped or CI-cache workflows produce an
+// artifact once and ship the tar to wherever it's needed.
+func WriteArchive(dest, ref string) error {
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return errors.Wrapf(err, "parsing reference %s", ref)
+	}
+
+	img, err := daemon.Image(tag)
+	if err != nil {
+		return errors.Wrap(err, "reading image from daemon")
+	}
+
+	layoutDir, err := ioutil.TempDir("", "skaffold-oci-layout")
+	if err != nil {
+		return errors.Wrap(err, "creating temp layout dir")
+	}
+	defer os.RemoveAll(layoutDir)
+
+	p, err := layout.Write(layoutDir, empty.Index)
+	if err != nil {
+		return errors.Wrap(err, "initializing OCI layout")
+	}
+	if err := p.AppendImage(img, layout.WithAnnotations(map[string]string{
+		"org.opencontainers.image.ref.name": tag.Name(),
+	})); err != nil {
+		return errors.Wrap(err, "appending image to OCI layout")
+	}
+
+	paths, err := walkFiles(layoutDir)
+	if err != nil {
+		return errors.Wrap(err, "listing layout files")
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return errors.Wrapf(err, "creating archive %s", dest)
+	}
+	defer out.Close()
+
+	return TarFiles(out, layoutDir, paths)
+}
+
+// WriteRemoteArchive does the same job as WriteArchive, but for an image
+// that was already pushed to a registry by a builder with no access to the
+// local daemon, such as kaniko.
+func WriteRemoteArchive(dest, ref string) error {
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return errors.Wrapf(err, "parsing reference %s", ref)
+	}
+
+	img, err := remote.Image(tag, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return errors.Wrap(err, "reading image from registry")
+	}
+
+	layoutDir, err := ioutil.TempDir("", "skaffold-oci-layout")
+	if err != nil {
+		return errors.Wrap(err, "creating temp layout dir")
+	}
+	defer os.RemoveAll(layoutDir)
+
+	p, err := layout.Write(layoutDir, empty.Index)
+	if err != nil {
+		return errors.Wrap(err, "initializing OCI layout")
+	}
+	if err := p.AppendImage(img, layout.WithAnnotations(map[string]string{
+		"org.opencontainers.image.ref.name": tag.Name(),
+	})); err != nil {
+		return errors.Wrap(err, "appending image to OCI layout")
+	}
+
+	paths, err := walkFiles(layoutDir)
+	if err != nil {
+		return errors.Wrap(err, "listing layout files")
+	}
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return errors.Wrapf(err, "creating archive %s", dest)
+	}
+	defer out.Close()
+
+	return TarFiles(out, layoutDir, paths)
+}
+
+// ImportArchive loads an OCI image layout archive written by WriteArchive
+// and either loads it into the local daemon (pushToRegistry is false) or
+// pushes it straight to a registry, returning the resulting build.Artifact
+// for each image found in the layout's index.
+func ImportArchive(path string, pushToRegistry bool) ([]build.Artifact, error) {
+	layoutDir, err := ioutil.TempDir("", "skaffold-oci-layout")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating temp layout dir")
+	}
+	defer os.RemoveAll(layoutDir)
+
+	if err := untar(path, layoutDir); err != nil {
+		return nil, errors.Wrapf(err, "extracting archive %s", path)
+	}
+
+	p, err := layout.FromPath(layoutDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading OCI layout")
+	}
+
+	index, err := p.ImageIndex()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading image index")
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading index manifest")
+	}
+
+	var artifacts []build.Artifact
+	for _, desc := range manifest.Manifests {
+		ref := desc.Annotations["org.opencontainers.image.ref.name"]
+		if ref == "" {
+			continue
+		}
+
+		img, err := index.Image(desc.Digest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading image %s", ref)
+		}
+
+		tag, err := name.ParseReference(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing reference %s", ref)
+		}
+
+		if pushToRegistry {
+			if err := remote.Write(tag, img, remote.WithAuthFromKeychain(keychain)); err != nil {
+				return nil, errors.Wrapf(err, "pushing %s", ref)
+			}
+		} else if _, err := daemon.Write(tag, img); err != nil {
+			return nil, errors.Wrapf(err, "loading %s into daemon", ref)
+		}
+
+		artifacts = append(artifacts, build.Artifact{
+			ImageName: tag.Context().Name(),
+			Tag:       ref,
+		})
+	}
+
+	return artifacts, nil
+}
+
+// untar extracts the tar archive at src into the directory dest.
+func untar(src, dest string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, header.Name)
+		if target != dest && !strings.HasPrefix(target, dest+string(os.PathSeparator)) {
+			return errors.Errorf("archive entry %q escapes destination %s", header.Name, dest)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, os.FileMode(header.Mode))
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			out.Close()
+		}
+	}
+}
+
+// walkFiles returns every regular file under root, as absolute paths.
+func walkFiles(root string) ([]string, error) {
+	var paths []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestUntarRejectsPathTraversal(t *testing.T) {
+	testutil.Run(t, "path traversal", func(t *testutil.T) {
+		tmpDir := t.NewTempDir()
+
+		archive := filepath.Join(tmpDir.Root(), "evil.tar")
+		writeTarWithEntry(t, archive, "../../etc/passwd", "pwned")
+
+		err := untar(archive, filepath.Join(tmpDir.Root(), "dest"))
+
+		t.CheckErrorAndDeepEqual(true, err, "", "")
+	})
+}
+
+func TestUntarRoundTrip(t *testing.T) {
+	testutil.Run(t, "round trip", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("layout/oci-layout", `{"imageLayoutVersion":"1.0.0"}`).
+			Write("layout/index.json", `{"schemaVersion":2,"manifests":[]}`)
+
+		layoutDir := filepath.Join(tmpDir.Root(), "layout")
+		paths, err := walkFiles(layoutDir)
+		if err != nil {
+			t.Fatalf("walking layout dir: %v", err)
+		}
+
+		archive := filepath.Join(tmpDir.Root(), "archive.tar")
+		out, err := os.Create(archive)
+		if err != nil {
+			t.Fatalf("creating archive: %v", err)
+		}
+		if err := TarFiles(out, layoutDir, paths); err != nil {
+			t.Fatalf("writing archive: %v", err)
+		}
+		out.Close()
+
+		dest := filepath.Join(tmpDir.Root(), "restored")
+
+		err = untar(archive, dest)
+
+		indexJSON, readErr := ioutil.ReadFile(filepath.Join(dest, "index.json"))
+		if readErr != nil {
+			t.Fatalf("reading restored index.json: %v", readErr)
+		}
+		t.CheckErrorAndDeepEqual(false, err, `{"schemaVersion":2,"manifests":[]}`, string(indexJSON))
+	})
+}
+
+// writeTarWithEntry writes a single-entry tar archive at dest whose entry
+// name is name, to exercise untar's handling of crafted (e.g. path
+// traversal) tar headers that the normal WriteArchive path never produces.
+func writeTarWithEntry(t *testutil.T, dest, name, contents string) {
+	f, err := os.Create(dest)
+	if err != nil {
+		t.Fatalf("creating %s: %v", dest, err)
+	}
+	defer f.Close()
+
+	tw := tar.NewWriter(f)
+	defer tw.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name: name,
+		Size: int64(len(contents)),
+		Mode: 0644,
+	}); err != nil {
+		t.Fatalf("writing tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(contents)); err != nil {
+		t.Fatalf("writing tar contents: %v", err)
+	}
+}
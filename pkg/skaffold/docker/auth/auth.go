@@ -0,0 +1,73 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth resolves registry credentials in the shape of containerd's
+// remotes/docker.NewDockerAuthorizer: given a registry hostname, an
+// Authorizer returns a bearer or basic credential. It does not handle
+// WWW-Authenticate challenges itself and does not cache credentials: every
+// call to Authorize resolves the credential again.
+package auth
+
+import (
+	"context"
+	"fmt"
+)
+
+// Credential is a resolved registry credential. Exactly one of
+// (Username, Password) or Token should be set.
+type Credential struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// Authorizer resolves a Credential for a registry hostname, such as
+// "gcr.io" or "012345678901.dkr.ecr.us-east1.amazonaws.com".
+type Authorizer interface {
+	Authorize(ctx context.Context, registry string) (*Credential, error)
+}
+
+// chain tries each Authorizer in order and returns the first credential
+// found. An Authorizer signals "not applicable" by returning a nil
+// Credential and a nil error.
+type chain []Authorizer
+
+func (c chain) Authorize(ctx context.Context, registry string) (*Credential, error) {
+	for _, authorizer := range c {
+		cred, err := authorizer.Authorize(ctx, registry)
+		if err != nil {
+			return nil, err
+		}
+		if cred != nil {
+			return cred, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no credentials found for registry %s", registry)
+}
+
+// NewDefaultAuthorizer returns the built-in Authorizer chain: docker's own
+// config.json first (so an explicit `docker login` always wins, and
+// `credHelpers`/`credsStore` entries go through the credential-helper shim),
+// then the registry-specific authorizers for GCR/GAR, ECR and ACR.
+func NewDefaultAuthorizer() Authorizer {
+	return chain{
+		NewConfigFileAuthorizer(),
+		NewGCRAuthorizer(),
+		NewECRAuthorizer(),
+		NewACRAuthorizer(),
+	}
+}
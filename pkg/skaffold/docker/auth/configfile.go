@@ -0,0 +1,101 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type dockerConfigFile struct {
+	Auths       map[string]dockerConfigAuth `json:"auths"`
+	CredsStore  string                      `json:"credsStore"`
+	CredHelpers map[string]string           `json:"credHelpers"`
+}
+
+type dockerConfigAuth struct {
+	Auth string `json:"auth"`
+}
+
+// configFileAuthorizer resolves credentials from ~/.docker/config.json,
+// the file `docker login` writes to. A registry configured with a
+// credsStore/credHelpers entry is delegated to the `helper:<binary>` shim.
+type configFileAuthorizer struct {
+	path string
+}
+
+// NewConfigFileAuthorizer returns an Authorizer backed by
+// ~/.docker/config.json (or $DOCKER_CONFIG/config.json, if set).
+func NewConfigFileAuthorizer() Authorizer {
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			dir = filepath.Join(home, ".docker")
+		}
+	}
+
+	return &configFileAuthorizer{path: filepath.Join(dir, "config.json")}
+}
+
+func (a *configFileAuthorizer) Authorize(ctx context.Context, registry string) (*Credential, error) {
+	content, err := ioutil.ReadFile(a.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", a.path)
+	}
+
+	var cfg dockerConfigFile
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", a.path)
+	}
+
+	if helperName, ok := cfg.CredHelpers[registry]; ok {
+		return NewHelperAuthorizer(helperName).Authorize(ctx, registry)
+	}
+	if cfg.CredsStore != "" {
+		if cred, err := NewHelperAuthorizer(cfg.CredsStore).Authorize(ctx, registry); err == nil && cred != nil {
+			return cred, nil
+		}
+	}
+
+	entry, ok := cfg.Auths[registry]
+	if !ok {
+		return nil, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding auth entry for %s", registry)
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("malformed auth entry for %s", registry)
+	}
+
+	return &Credential{Username: parts[0], Password: parts[1]}, nil
+}
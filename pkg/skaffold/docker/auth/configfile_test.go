@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestConfigFileAuthorizer(t *testing.T) {
+	testutil.Run(t, "found", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("config.json", `{
+			"auths": {
+				"my-registry.io": {
+					"auth": "dXNlcjpwYXNzd29yZA=="
+				}
+			}
+		}`)
+
+		a := &configFileAuthorizer{path: filepath.Join(tmpDir.Root(), "config.json")}
+		cred, err := a.Authorize(context.Background(), "my-registry.io")
+
+		t.CheckErrorAndDeepEqual(false, err, &Credential{Username: "user", Password: "password"}, cred)
+	})
+
+	testutil.Run(t, "not found", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("config.json", `{"auths": {}}`)
+
+		a := &configFileAuthorizer{path: filepath.Join(tmpDir.Root(), "config.json")}
+		cred, err := a.Authorize(context.Background(), "my-registry.io")
+
+		t.CheckErrorAndDeepEqual(false, err, (*Credential)(nil), cred)
+	})
+
+	testutil.Run(t, "missing file", func(t *testutil.T) {
+		a := &configFileAuthorizer{path: "/does/not/exist/config.json"}
+		cred, err := a.Authorize(context.Background(), "my-registry.io")
+
+		t.CheckErrorAndDeepEqual(false, err, (*Credential)(nil), cred)
+	})
+}
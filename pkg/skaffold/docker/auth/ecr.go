@@ -0,0 +1,76 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/pkg/errors"
+)
+
+// ecrHost matches an ECR registry hostname, e.g.
+// 012345678901.dkr.ecr.us-east-1.amazonaws.com, and captures the region.
+var ecrHost = regexp.MustCompile(`^\d+\.dkr\.ecr\.([a-z0-9-]+)\.amazonaws\.com$`)
+
+// ecrAuthorizer mints a short-lived ECR token through ECR's
+// GetAuthorizationToken, using whatever AWS credentials are already
+// available in the environment (env vars, shared config, instance role).
+type ecrAuthorizer struct{}
+
+// NewECRAuthorizer returns an Authorizer for Amazon ECR registries.
+func NewECRAuthorizer() Authorizer {
+	return &ecrAuthorizer{}
+}
+
+func (a *ecrAuthorizer) Authorize(ctx context.Context, registry string) (*Credential, error) {
+	match := ecrHost.FindStringSubmatch(registry)
+	if match == nil {
+		return nil, nil
+	}
+	region := match[1]
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(region)})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AWS session")
+	}
+
+	out, err := ecr.New(sess).GetAuthorizationTokenWithContext(ctx, &ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "calling ecr:GetAuthorizationToken")
+	}
+	if len(out.AuthorizationData) == 0 {
+		return nil, errors.New("ecr:GetAuthorizationToken returned no credentials")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(aws.StringValue(out.AuthorizationData[0].AuthorizationToken))
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding ECR authorization token")
+	}
+
+	parts := strings.SplitN(string(decoded), ":", 2)
+	if len(parts) != 2 {
+		return nil, errors.New("malformed ECR authorization token")
+	}
+
+	return &Credential{Username: parts[0], Password: parts[1]}, nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/compute/metadata"
+	"golang.org/x/oauth2/google"
+)
+
+// gcrHosts are the registry hostnames served by Google Container/Artifact
+// Registry that accept a GCP access token as the password, with "oauth2accesstoken"
+// as the username.
+var gcrHosts = []string{"gcr.io", "pkg.dev"}
+
+// gcrAuthorizer resolves credentials for GCR/GAR, preferring the GCE/GKE
+// metadata server when running on GCE and falling back to Application
+// Default Credentials (a service account key, `gcloud auth` login, or
+// workload identity).
+type gcrAuthorizer struct{}
+
+// NewGCRAuthorizer returns an Authorizer for Google Container Registry and
+// Artifact Registry hosts.
+func NewGCRAuthorizer() Authorizer {
+	return &gcrAuthorizer{}
+}
+
+func (a *gcrAuthorizer) Authorize(ctx context.Context, registry string) (*Credential, error) {
+	if !isGCRHost(registry) {
+		return nil, nil
+	}
+
+	if metadata.OnGCE() {
+		token, err := metadata.Get("instance/service-accounts/default/token")
+		if err == nil && token != "" {
+			return &Credential{Username: "oauth2accesstoken", Password: token}, nil
+		}
+	}
+
+	ts, err := google.DefaultTokenSource(ctx, "https://www.googleapis.com/auth/cloud-platform")
+	if err != nil {
+		// No Google credentials configured: let the next authorizer in the
+		// chain try instead.
+		return nil, nil
+	}
+
+	token, err := ts.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credential{Username: "oauth2accesstoken", Password: token.AccessToken}, nil
+}
+
+func isGCRHost(registry string) bool {
+	for _, host := range gcrHosts {
+		if registry == host || strings.HasSuffix(registry, "."+host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+type helperCredentials struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// helperAuthorizer execs a docker credential helper binary
+// (docker-credential-<name>) the way `docker login`-managed config.json
+// entries reference them, so any helper already set up for the docker CLI
+// works for skaffold too.
+type helperAuthorizer struct {
+	binary string
+}
+
+// NewHelperAuthorizer returns an Authorizer that shells out to
+// docker-credential-<name> to resolve credentials for a registry.
+func NewHelperAuthorizer(name string) Authorizer {
+	return &helperAuthorizer{binary: fmt.Sprintf("docker-credential-%s", name)}
+}
+
+func (a *helperAuthorizer) Authorize(ctx context.Context, registry string) (*Credential, error) {
+	cmd := exec.CommandContext(ctx, a.binary, "get")
+	cmd.Stdin = bytes.NewBufferString(registry)
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "running %s get", a.binary)
+	}
+
+	var creds helperCredentials
+	if err := json.Unmarshal(out, &creds); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s output", a.binary)
+	}
+
+	return &Credential{Username: creds.Username, Password: creds.Secret}, nil
+}
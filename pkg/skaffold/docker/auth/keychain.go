@@ -0,0 +1,51 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"context"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+)
+
+// keychain adapts an Authorizer to go-containerregistry's authn.Keychain,
+// so every place that talks to a registry through go-containerregistry
+// (pushes, pulls, digest/timestamp rewrites, archive import/export) goes
+// through the same credential resolution.
+type keychain struct {
+	authorizer Authorizer
+}
+
+// NewKeychain wraps authorizer as an authn.Keychain.
+func NewKeychain(authorizer Authorizer) authn.Keychain {
+	return &keychain{authorizer: authorizer}
+}
+
+func (k *keychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	cred, err := k.authorizer.Authorize(context.Background(), target.RegistryStr())
+	if err != nil {
+		return authn.Anonymous, nil
+	}
+
+	if cred.Token != "" {
+		return authn.FromConfig(authn.AuthConfig{RegistryToken: cred.Token}), nil
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username: cred.Username,
+		Password: cred.Password,
+	}), nil
+}
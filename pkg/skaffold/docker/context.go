@@ -38,9 +38,18 @@ func CreateDockerTarContext(ctx context.Context, w io.Writer, workspace string,
 		p = append(p, filepath.Join(workspace, path))
 	}
 
-	if err := util.CreateTar(w, workspace, p); err != nil {
+	if err := TarFiles(w, workspace, p); err != nil {
 		return errors.Wrap(err, "creating tar gz")
 	}
 
 	return nil
 }
+
+// TarFiles writes paths (absolute, all rooted under root) to w as a tar
+// archive with entries relative to root. It's the shared tar-writing step
+// behind CreateDockerTarContext, reused as-is by the OCI image layout
+// writer, which tars an entire layout directory instead of a filtered list
+// of dependencies.
+func TarFiles(w io.Writer, root string, paths []string) error {
+	return util.CreateTar(w, root, paths)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/daemon"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/docker/auth"
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// keychain is shared by every function in this file that talks to a
+// registry, so credentials are resolved the same way for a timestamp
+// rewrite as for a push.
+var keychain = auth.NewKeychain(auth.NewDefaultAuthorizer())
+
+// ResolveOutputTimestamp turns an OutputTimestamp setting into the concrete
+// instant that should be baked into a built image's config and layer
+// history. dependencies are paths relative to workspace, as returned by
+// GetDependencies.
+func ResolveOutputTimestamp(ts latest.OutputTimestamp, workspace string, dependencies []string) (time.Time, error) {
+	switch ts {
+	case latest.ZeroOutputTimestamp:
+		return time.Unix(0, 0), nil
+
+	case latest.SourceOutputTimestamp:
+		return sourceTimestamp(workspace, dependencies)
+
+	case latest.BuildOutputTimestamp:
+		return time.Now(), nil
+
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// sourceTimestamp returns the most recent modification time among the
+// artifact's dependencies. Builders that can't enumerate dependencies (such
+// as kaniko, which has no local Docker daemon to ask) pass nil and fall
+// back to the git commit time of workspace.
+func sourceTimestamp(workspace string, dependencies []string) (time.Time, error) {
+	if dependencies == nil {
+		return gitCommitTimestamp(workspace)
+	}
+
+	var latestModTime time.Time
+
+	for _, dep := range dependencies {
+		info, err := os.Stat(filepath.Join(workspace, dep))
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "stat %s", dep)
+		}
+		if info.ModTime().After(latestModTime) {
+			latestModTime = info.ModTime()
+		}
+	}
+
+	return latestModTime, nil
+}
+
+// gitCommitTimestamp returns the commit time of workspace's current HEAD,
+// for builders that can't enumerate individual dependency files.
+func gitCommitTimestamp(workspace string) (time.Time, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	cmd.Dir = workspace
+
+	out, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "%s is not a git repository, can't resolve a source timestamp without dependencies", workspace)
+	}
+
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "parsing git commit time")
+	}
+
+	return time.Unix(sec, 0), nil
+}
+
+// SetImageTimestamp rewrites a locally built image's config `created` field,
+// and every layer history entry's `created`, to t, and writes the result
+// back to the daemon under the same reference. Two builds of the same
+// source tree with the same OutputTimestamp therefore produce byte-identical
+// image configs and digests.
+func SetImageTimestamp(ref string, t time.Time) error {
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return errors.Wrapf(err, "parsing reference %s", ref)
+	}
+
+	img, err := daemon.Image(tag)
+	if err != nil {
+		return errors.Wrap(err, "reading image from daemon")
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return errors.Wrap(err, "reading image config")
+	}
+	cfg = cfg.DeepCopy()
+	cfg.Created = v1.Time{Time: t}
+	for i := range cfg.History {
+		cfg.History[i].Created = v1.Time{Time: t}
+	}
+
+	img, err = mutate.ConfigFile(img, cfg)
+	if err != nil {
+		return errors.Wrap(err, "rewriting image config")
+	}
+
+	if _, err := daemon.Write(tag, img); err != nil {
+		return errors.Wrap(err, "writing image back to daemon")
+	}
+
+	return nil
+}
+
+// SetRemoteImageTimestamp does the same rewrite as SetImageTimestamp, but
+// for an image that was already pushed to a registry by a builder with no
+// access to the local daemon, such as kaniko. The rewritten image is pushed
+// back to the same reference, which changes its digest.
+func SetRemoteImageTimestamp(ref string, t time.Time) error {
+	tag, err := name.ParseReference(ref)
+	if err != nil {
+		return errors.Wrapf(err, "parsing reference %s", ref)
+	}
+
+	img, err := remote.Image(tag, remote.WithAuthFromKeychain(keychain))
+	if err != nil {
+		return errors.Wrap(err, "reading image from registry")
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return errors.Wrap(err, "reading image config")
+	}
+	cfg = cfg.DeepCopy()
+	cfg.Created = v1.Time{Time: t}
+	for i := range cfg.History {
+		cfg.History[i].Created = v1.Time{Time: t}
+	}
+
+	img, err = mutate.ConfigFile(img, cfg)
+	if err != nil {
+		return errors.Wrap(err, "rewriting image config")
+	}
+
+	return remote.Write(tag, img, remote.WithAuthFromKeychain(keychain))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+	"github.com/GoogleContainerTools/skaffold/testutil"
+)
+
+func TestResolveOutputTimestampZero(t *testing.T) {
+	testutil.Run(t, "zero", func(t *testutil.T) {
+		got, err := ResolveOutputTimestamp(latest.ZeroOutputTimestamp, "", nil)
+
+		t.CheckErrorAndDeepEqual(false, err, time.Unix(0, 0), got)
+	})
+}
+
+func TestResolveOutputTimestampSource(t *testing.T) {
+	testutil.Run(t, "source", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().
+			Write("older", "older").
+			Write("newer", "newer")
+
+		older := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+		newer := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+		if err := os.Chtimes(filepath.Join(tmpDir.Root(), "older"), older, older); err != nil {
+			t.Fatalf("setting mtime: %v", err)
+		}
+		if err := os.Chtimes(filepath.Join(tmpDir.Root(), "newer"), newer, newer); err != nil {
+			t.Fatalf("setting mtime: %v", err)
+		}
+
+		got, err := ResolveOutputTimestamp(latest.SourceOutputTimestamp, tmpDir.Root(), []string{"older", "newer"})
+
+		t.CheckErrorAndDeepEqual(false, err, newer, got)
+	})
+}
+
+func TestResolveOutputTimestampSourceFallsBackToGitCommitTime(t *testing.T) {
+	testutil.Run(t, "source without dependencies", func(t *testutil.T) {
+		tmpDir := t.NewTempDir().Write("Dockerfile", "FROM scratch")
+		workspace := tmpDir.Root()
+
+		run(t, workspace, "git", "init")
+		run(t, workspace, "git", "config", "user.email", "test@example.com")
+		run(t, workspace, "git", "config", "user.name", "test")
+		run(t, workspace, "git", "add", "Dockerfile")
+		run(t, workspace, "git", "commit", "-m", "initial")
+
+		out, err := exec.Command("git", "-C", workspace, "log", "-1", "--format=%ct").Output()
+		if err != nil {
+			t.Fatalf("reading commit time: %v", err)
+		}
+
+		got, err := ResolveOutputTimestamp(latest.SourceOutputTimestamp, workspace, nil)
+
+		wantSec := strings.TrimSpace(string(out))
+		gotSec := fmt.Sprintf("%d", got.Unix())
+		t.CheckErrorAndDeepEqual(false, err, wantSec, gotSec)
+	})
+}
+
+func run(t interface{ Fatalf(string, ...interface{}) }, dir, name string, args ...string) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%s %v: %v\n%s", name, args, err, out)
+	}
+}
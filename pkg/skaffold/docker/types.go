@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package docker
+
+import (
+	"context"
+	"io"
+
+	"github.com/GoogleContainerTools/skaffold/pkg/skaffold/schema/latest"
+)
+
+// Config represents the parts of the global build config that the docker
+// builders need, independently of which one (local, kaniko, ...) is running.
+type Config struct {
+	UseDockerCLI    bool
+	UseBuildkit     bool
+	BuildkitAddr    string
+	OutputTimestamp latest.OutputTimestamp
+	// ArchiveOutput, when set, makes builders write each built image to this
+	// path as an OCI image layout archive instead of (or in addition to)
+	// pushing it to a registry.
+	ArchiveOutput string
+}
+
+// LocalDaemon talks to the local docker daemon to build, push and tag images.
+type LocalDaemon interface {
+	Build(ctx context.Context, out io.Writer, workspace string, a *latest.DockerArtifact, ref string) (string, error)
+	Push(ctx context.Context, out io.Writer, ref string) (string, error)
+	Close() error
+}
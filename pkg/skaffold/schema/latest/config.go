@@ -0,0 +1,210 @@
+/*
+Copyright 2019 The Skaffold Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package latest
+
+import "fmt"
+
+// Pipeline describes a Skaffold pipeline.
+type Pipeline struct {
+	Build  BuildConfig  `yaml:"build,omitempty"`
+	Deploy DeployConfig `yaml:"deploy,omitempty"`
+}
+
+// DeployConfig contains all the configuration needed by the deploy steps.
+type DeployConfig struct {
+}
+
+// BuildConfig contains all the configuration for the build steps.
+type BuildConfig struct {
+	Artifacts       []*Artifact     `yaml:"artifacts,omitempty"`
+	OutputTimestamp OutputTimestamp `yaml:"outputTimestamp,omitempty"`
+	// ArchiveOutput, when set, makes builders that support it (local docker,
+	// bazel and jib artifacts, and kaniko) write each built image to this
+	// path as an OCI image layout archive instead of (or in addition to)
+	// pushing it to a registry. Buildpack and llb artifacts ignore it.
+	ArchiveOutput string `yaml:"archiveOutput,omitempty"`
+	BuildType     `yaml:",inline"`
+}
+
+// SetDefaults copies the pipeline-wide OutputTimestamp and ArchiveOutput into
+// KanikoBuild where it doesn't explicitly override them, so that
+// build.outputTimestamp/build.archiveOutput also apply to kaniko builds.
+// TektonBuild has neither setting and is left untouched.
+func (c *BuildConfig) SetDefaults() {
+	if c.KanikoBuild == nil {
+		return
+	}
+	if c.KanikoBuild.OutputTimestamp == "" {
+		c.KanikoBuild.OutputTimestamp = c.OutputTimestamp
+	}
+	if c.KanikoBuild.ArchiveOutput == "" {
+		c.KanikoBuild.ArchiveOutput = c.ArchiveOutput
+	}
+}
+
+// OutputTimestamp controls what timestamp gets baked into a built image's
+// config and layer history, so that two builds over the same source tree
+// can produce byte-identical images.
+type OutputTimestamp string
+
+const (
+	// ZeroOutputTimestamp resets every timestamp to the Unix epoch.
+	ZeroOutputTimestamp OutputTimestamp = "Zero"
+	// SourceOutputTimestamp uses the most recent modification time among the
+	// artifact's dependencies (or the workspace's git commit time).
+	SourceOutputTimestamp OutputTimestamp = "SourceTimestamp"
+	// BuildOutputTimestamp uses the current time at the moment the timestamp
+	// is resolved, which happens separately for each artifact.
+	BuildOutputTimestamp OutputTimestamp = "BuildTimestamp"
+)
+
+// Validate checks that the OutputTimestamp is one of the supported values.
+// An empty value is valid and means skaffold leaves timestamps untouched.
+func (o OutputTimestamp) Validate() error {
+	switch o {
+	case "", ZeroOutputTimestamp, SourceOutputTimestamp, BuildOutputTimestamp:
+		return nil
+	default:
+		return fmt.Errorf("invalid build.outputTimestamp %q: must be one of %q, %q or %q", o, ZeroOutputTimestamp, SourceOutputTimestamp, BuildOutputTimestamp)
+	}
+}
+
+// BuildType contains the specific implementation and parameters needed
+// for the build step. Only one field should be populated.
+type BuildType struct {
+	LocalBuild       *LocalBuild       `yaml:"local,omitempty"`
+	GoogleCloudBuild *GoogleCloudBuild `yaml:"googleCloudBuild,omitempty"`
+	Cluster          *ClusterDetails   `yaml:"cluster,omitempty"`
+	KanikoBuild      *KanikoBuild      `yaml:"kaniko,omitempty"`
+	TektonBuild      *TektonBuild      `yaml:"tekton,omitempty"`
+}
+
+// LocalBuild describes how to do a build on the local docker daemon
+// and optionally push to a repository.
+type LocalBuild struct {
+	Push         *bool  `yaml:"push,omitempty"`
+	UseDockerCLI bool   `yaml:"useDockerCLI,omitempty"`
+	UseBuildkit  bool   `yaml:"useBuildkit,omitempty"`
+	BuildkitAddr string `yaml:"buildkitAddr,omitempty"`
+}
+
+// GoogleCloudBuild describes how to do a remote build on
+// Google Cloud Build.
+type GoogleCloudBuild struct {
+	ProjectID string `yaml:"projectId,omitempty"`
+}
+
+// ClusterDetails describes how to do an on-cluster build.
+type ClusterDetails struct {
+	PullSecretName string `yaml:"pullSecretName,omitempty"`
+	Namespace      string `yaml:"namespace,omitempty"`
+}
+
+// KanikoBuild describes how to do an on-cluster build with kaniko.
+type KanikoBuild struct {
+	PullSecretName  string          `yaml:"pullSecretName,omitempty"`
+	Namespace       string          `yaml:"namespace,omitempty"`
+	AdditionalFlags []string        `yaml:"flags,omitempty"`
+	Cache           *KanikoCache    `yaml:"cache,omitempty"`
+	OutputTimestamp OutputTimestamp `yaml:"outputTimestamp,omitempty"`
+	ArchiveOutput   string          `yaml:"archiveOutput,omitempty"`
+	// UseAuthorizer makes skaffold mint a short-lived registry credential
+	// through pkg/skaffold/docker/auth for each kaniko pod and inject it as
+	// an env var, instead of mounting PullSecretName into every pod.
+	UseAuthorizer bool `yaml:"useAuthorizer,omitempty"`
+}
+
+// KanikoCache describes the kaniko layer cache.
+type KanikoCache struct {
+	Repo string `yaml:"repo,omitempty"`
+}
+
+// TektonBuild describes how to do an on-cluster build by submitting a
+// Tekton PipelineRun to a pre-installed pipeline, reusing a team's existing
+// Tekton build infrastructure instead of raw kaniko pods.
+type TektonBuild struct {
+	PipelineName       string `yaml:"pipelineName,omitempty"`
+	Namespace          string `yaml:"namespace,omitempty"`
+	ServiceAccountName string `yaml:"serviceAccount,omitempty"`
+	Workspace          string `yaml:"workspace,omitempty"`
+}
+
+// Artifact are the items that need to be built, along with the context in
+// which they should be built.
+type Artifact struct {
+	ImageName    string `yaml:"image,omitempty"`
+	Workspace    string `yaml:"context,omitempty"`
+	ArtifactType `yaml:",inline"`
+}
+
+// ArtifactType describes the type of artifact to build. Only one field
+// should be populated.
+type ArtifactType struct {
+	DockerArtifact    *DockerArtifact    `yaml:"docker,omitempty"`
+	BazelArtifact     *BazelArtifact     `yaml:"bazel,omitempty"`
+	JibMavenArtifact  *JibMavenArtifact  `yaml:"jibMaven,omitempty"`
+	JibGradleArtifact *JibGradleArtifact `yaml:"jibGradle,omitempty"`
+	BuildpackArtifact *BuildpackArtifact `yaml:"buildpack,omitempty"`
+	LLBArtifact       *LLBArtifact       `yaml:"llb,omitempty"`
+}
+
+// DockerArtifact describes an artifact built from a Dockerfile,
+// usually using `docker build`.
+type DockerArtifact struct {
+	DockerfilePath string             `yaml:"dockerfile,omitempty"`
+	BuildArgs      map[string]*string `yaml:"buildArgs,omitempty"`
+	CacheFrom      []string           `yaml:"cacheFrom,omitempty"`
+	Target         string             `yaml:"target,omitempty"`
+}
+
+// BazelArtifact describes an artifact built with Bazel.
+type BazelArtifact struct {
+	BuildTarget string   `yaml:"target,omitempty"`
+	BuildArgs   []string `yaml:"args,omitempty"`
+}
+
+// JibMavenArtifact describes an artifact built with Jib through Maven.
+type JibMavenArtifact struct {
+	Module  string `yaml:"module,omitempty"`
+	Profile string `yaml:"profile,omitempty"`
+}
+
+// JibGradleArtifact describes an artifact built with Jib through Gradle.
+type JibGradleArtifact struct {
+	Project string `yaml:"project,omitempty"`
+}
+
+// BuildpackArtifact describes an artifact built with Cloud Native Buildpacks,
+// without requiring a Dockerfile.
+type BuildpackArtifact struct {
+	Builder    string            `yaml:"builder,omitempty"`
+	RunImage   string            `yaml:"runImage,omitempty"`
+	Buildpacks []string          `yaml:"buildpacks,omitempty"`
+	Env        map[string]string `yaml:"env,omitempty"`
+	CacheImage string            `yaml:"cacheImage,omitempty"`
+}
+
+// LLBArtifact describes an artifact built by submitting an LLB graph
+// directly to a BuildKit daemon, bypassing the Dockerfile frontend. Power
+// users can describe a build as a sequence of ops in `skaffold.yaml`,
+// starting `FROM` a base image and running commands against it; Image and
+// Commands mirror the minimal `llb.Image(...).Run(llb.Shlex(...))` shape,
+// enough to cover single-stage programmatic builds without hand-writing Go.
+type LLBArtifact struct {
+	Image    string   `yaml:"image,omitempty"`
+	Commands []string `yaml:"commands,omitempty"`
+}